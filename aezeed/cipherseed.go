@@ -0,0 +1,351 @@
+// Package aezeed implements an enciphered, passphrase-protected mnemonic
+// scheme layered on top of the bip39 package's English wordlist, modeled on
+// LND's cipher seed. Unlike a plain BIP39 mnemonic, a cipher seed carries a
+// wallet birthday that lets restore code skip chain history predating the
+// wallet, and is useless without its passphrase even if the mnemonic itself
+// is stolen.
+package aezeed
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+const (
+	// versionSize, birthdaySize, entropySize and reservedSize are the
+	// fields making up the plaintext enciphered into every cipher seed.
+	// reservedSize is left zeroed for now, for future versions to extend
+	// the format without changing its word count.
+	versionSize  = 1
+	birthdaySize = 2
+	entropySize  = 16
+	reservedSize = 5
+
+	// saltSize and crcSize are the fields appended to the ciphertext in
+	// the clear.
+	saltSize = 5
+	crcSize  = 4
+
+	// encipheredSize is the size of the plaintext payload: version,
+	// birthday, entropy and reserved bytes, enciphered together.
+	encipheredSize = versionSize + birthdaySize + entropySize + reservedSize
+
+	// cipherTextSize is encipheredSize plus the Poly1305 tag chacha20poly1305
+	// appends to authenticate it.
+	cipherTextSize = encipheredSize + chacha20poly1305.Overhead
+
+	// payloadSize is the total size of the mnemonic payload: the
+	// ciphertext with its salt and CRC-32 checksum appended.
+	payloadSize = cipherTextSize + saltSize + crcSize
+
+	// numMnemonicWords is the number of words a cipher seed is always
+	// rendered as: enough 11-bit words to hold payloadSize's bits, rounded
+	// up.
+	numMnemonicWords = (payloadSize*8 + 10) / 11
+
+	// wordBitLength is the number of bits numMnemonicWords actually holds,
+	// and padBitLength is the slack above payloadSize*8 that rounding up
+	// to a whole word leaves at the front of the buffer - mirroring how
+	// the parent package's addChecksum output is right-aligned whenever
+	// its own bit length isn't a whole number of words.
+	wordBitLength = numMnemonicWords * 11
+	padBitLength  = wordBitLength - payloadSize*8
+
+	// bufSize is the byte buffer size needed to hold wordBitLength bits.
+	bufSize = (wordBitLength + 7) / 8
+)
+
+// scrypt parameters for the passphrase-derived encryption key. A higher N
+// trades seed derivation time for resistance to brute-forcing a stolen
+// mnemonic.
+const (
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+
+	scryptKeyLen = chacha20poly1305.KeySize
+)
+
+// genesisDate is the fixed epoch Birthday is measured from, in whole days.
+var genesisDate = time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// zeroNonce is the fixed nonce used for every chacha20poly1305 call. This is
+// only safe because the key each nonce is paired with is derived, via
+// scrypt, from a salt generated fresh on every ToMnemonic call: a given
+// (key, nonce) pair is therefore never reused across two different
+// ciphertexts.
+var zeroNonce [chacha20poly1305.NonceSize]byte
+
+var (
+	// ErrInvalidPass is returned by ToCipherSeed when a mnemonic fails to
+	// decipher, almost always because the wrong passphrase was supplied.
+	ErrInvalidPass = errors.New("aezeed: invalid passphrase")
+
+	// ErrChecksumMismatch is returned when the CRC-32 over a mnemonic's
+	// ciphertext and salt does not match, indicating a corrupted mnemonic.
+	// It is checked before deciphering, so the passphrase plays no part.
+	ErrChecksumMismatch = errors.New("aezeed: checksum mismatch")
+
+	// ErrWordCount is returned when a Mnemonic does not contain exactly
+	// numMnemonicWords words.
+	ErrWordCount = fmt.Errorf("aezeed: mnemonic must contain %d words", numMnemonicWords)
+
+	// ErrUnknownWord is returned when a Mnemonic contains a word outside
+	// the BIP39 English wordlist.
+	ErrUnknownWord = errors.New("aezeed: mnemonic contains an unknown word")
+)
+
+// CipherSeed is the plaintext material enciphered into a Mnemonic: a
+// version byte, a wallet birthday, and 16 bytes of entropy.
+type CipherSeed struct {
+	// InternalVersion is the version of the cipher seed scheme used to
+	// encipher this seed.
+	InternalVersion uint8
+
+	// Birthday is the number of days since genesisDate that this seed was
+	// created.
+	Birthday uint16
+
+	// Entropy is the raw wallet entropy this seed protects.
+	Entropy [entropySize]byte
+
+	// salt is generated fresh by every ToMnemonic call and mixed into the
+	// scrypt key derivation so that the same passphrase never derives the
+	// same key for two different seeds.
+	salt [saltSize]byte
+}
+
+// Mnemonic is a numMnemonicWords-word rendering of an enciphered CipherSeed.
+type Mnemonic [numMnemonicWords]string
+
+// New creates a CipherSeed of the given version from entropy and birthday.
+// entropy must be entropySize (16) bytes.
+func New(version uint8, entropy []byte, birthday time.Time) (*CipherSeed, error) {
+	if len(entropy) != entropySize {
+		return nil, errors.New("aezeed: entropy must be 16 bytes")
+	}
+
+	c := &CipherSeed{
+		InternalVersion: version,
+		Birthday:        daysSinceGenesis(birthday),
+	}
+	copy(c.Entropy[:], entropy)
+
+	return c, nil
+}
+
+// daysSinceGenesis clamps to zero rather than going negative, since a
+// birthday before genesisDate isn't representable.
+func daysSinceGenesis(t time.Time) uint16 {
+	days := t.UTC().Sub(genesisDate).Hours() / 24
+	if days < 0 {
+		return 0
+	}
+	return uint16(days)
+}
+
+// ToMnemonic enciphers c under a key derived from pass and renders the
+// result as a numMnemonicWords-word mnemonic drawn from the BIP39 English
+// wordlist.
+func (c *CipherSeed) ToMnemonic(pass []byte) (Mnemonic, error) {
+	var mnemonic Mnemonic
+
+	if _, err := rand.Read(c.salt[:]); err != nil {
+		return mnemonic, err
+	}
+
+	key, err := scrypt.Key(pass, c.salt[:], scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return mnemonic, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return mnemonic, err
+	}
+
+	ciphertext := aead.Seal(nil, zeroNonce[:], c.encode(), nil)
+
+	payload := make([]byte, 0, payloadSize)
+	payload = append(payload, ciphertext...)
+	payload = append(payload, c.salt[:]...)
+	payload = appendChecksum(payload)
+
+	return encodeMnemonic(payload)
+}
+
+// ToCipherSeed verifies the CRC-32 checksum of m's ciphertext and salt, then
+// deciphers m under a key derived from pass.
+func (m Mnemonic) ToCipherSeed(pass []byte) (*CipherSeed, error) {
+	payload, err := decodeMnemonic(m)
+	if err != nil {
+		return nil, err
+	}
+
+	body := payload[:len(payload)-crcSize]
+	wantChecksum := binary.BigEndian.Uint32(payload[len(payload)-crcSize:])
+	if crc32.ChecksumIEEE(body) != wantChecksum {
+		return nil, ErrChecksumMismatch
+	}
+
+	ciphertext := body[:cipherTextSize]
+	var salt [saltSize]byte
+	copy(salt[:], body[cipherTextSize:])
+
+	key, err := scrypt.Key(pass, salt[:], scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, zeroNonce[:], ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidPass
+	}
+
+	c := decode(plaintext)
+	c.salt = salt
+	return c, nil
+}
+
+// ChangePass deciphers m under oldPass and re-enciphers the resulting
+// CipherSeed under newPass with a freshly generated salt. It returns
+// ErrInvalidPass (via ToCipherSeed) if oldPass does not actually decipher m.
+func ChangePass(m Mnemonic, oldPass, newPass []byte) (Mnemonic, error) {
+	c, err := m.ToCipherSeed(oldPass)
+	if err != nil {
+		return Mnemonic{}, err
+	}
+
+	return c.ToMnemonic(newPass)
+}
+
+// encode packs the cipher seed's version, birthday and entropy into the
+// plaintext payload that gets enciphered. The reserved bytes are always
+// zero in this version.
+func (c *CipherSeed) encode() []byte {
+	payload := make([]byte, encipheredSize)
+	payload[0] = c.InternalVersion
+	binary.BigEndian.PutUint16(payload[versionSize:versionSize+birthdaySize], c.Birthday)
+	copy(payload[versionSize+birthdaySize:], c.Entropy[:])
+	return payload
+}
+
+// decode is the inverse of encode.
+func decode(payload []byte) *CipherSeed {
+	c := &CipherSeed{
+		InternalVersion: payload[0],
+		Birthday:        binary.BigEndian.Uint16(payload[versionSize : versionSize+birthdaySize]),
+	}
+	copy(c.Entropy[:], payload[versionSize+birthdaySize:versionSize+birthdaySize+entropySize])
+	return c
+}
+
+// appendChecksum appends the CRC-32 (IEEE) checksum of payload to itself.
+func appendChecksum(payload []byte) []byte {
+	checksum := crc32.ChecksumIEEE(payload)
+	checksumBytes := make([]byte, crcSize)
+	binary.BigEndian.PutUint32(checksumBytes, checksum)
+	return append(payload, checksumBytes...)
+}
+
+// encodeMnemonic packs payload (expected to be payloadSize bytes) into
+// numMnemonicWords BIP39 English words, 11 bits per word. payload's bytes
+// are placed right-aligned within the word buffer - any padding needed to
+// round payloadSize's bits up to a whole number of words goes at the front
+// - matching the parent package's own convention for non-byte-aligned bit
+// lengths. No further checksum is needed here since payload already carries
+// its own CRC-32.
+func encodeMnemonic(payload []byte) (Mnemonic, error) {
+	var m Mnemonic
+	if len(payload) != payloadSize {
+		return m, errors.New("aezeed: payload does not match payloadSize")
+	}
+
+	buf := make([]byte, bufSize)
+	for i, b := range payload {
+		writeBits(buf, padBitLength+i*8, 8, int(b))
+	}
+
+	for i := 0; i < numMnemonicWords; i++ {
+		m[i] = wordlists.English[readBits(buf, i*11, 11)]
+	}
+	return m, nil
+}
+
+// decodeMnemonic is the inverse of encodeMnemonic.
+func decodeMnemonic(m Mnemonic) ([]byte, error) {
+	for _, word := range m {
+		if word == "" {
+			return nil, ErrWordCount
+		}
+	}
+
+	indexOf := englishWordIndex()
+	buf := make([]byte, bufSize)
+	for i, word := range m {
+		index, ok := indexOf[word]
+		if !ok {
+			return nil, ErrUnknownWord
+		}
+		writeBits(buf, i*11, 11, index)
+	}
+
+	payload := make([]byte, payloadSize)
+	for i := range payload {
+		payload[i] = byte(readBits(buf, padBitLength+i*8, 8))
+	}
+	return payload, nil
+}
+
+var wordIndex map[string]int
+
+// englishWordIndex lazily builds and caches the word->index lookup for the
+// BIP39 English wordlist.
+func englishWordIndex() map[string]int {
+	if wordIndex != nil {
+		return wordIndex
+	}
+	wordIndex = make(map[string]int, len(wordlists.English))
+	for i, w := range wordlists.English {
+		wordIndex[w] = i
+	}
+	return wordIndex
+}
+
+// readBits extracts a bitCount-bit big-endian run from data starting at
+// bitOffset.
+func readBits(data []byte, bitOffset, bitCount int) int {
+	value := 0
+	for i := 0; i < bitCount; i++ {
+		pos := bitOffset + i
+		bit := (data[pos/8] >> uint(7-pos%8)) & 1
+		value = value<<1 | int(bit)
+	}
+	return value
+}
+
+// writeBits packs the low bitCount bits of value into data at bitOffset,
+// big-endian.
+func writeBits(data []byte, bitOffset, bitCount, value int) {
+	for i := 0; i < bitCount; i++ {
+		bit := (value >> uint(bitCount-1-i)) & 1
+		if bit != 0 {
+			pos := bitOffset + i
+			data[pos/8] |= 1 << uint(7-pos%8)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package aezeed_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rhizomplatform/go-bip39/aezeed"
+)
+
+func TestRoundTrip(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x42}, 16)
+	birthday := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	c, err := aezeed.New(0, entropy, birthday)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	mnemonic, err := c.ToMnemonic([]byte("pass"))
+	if err != nil {
+		t.Fatalf("ToMnemonic returned error: %v", err)
+	}
+
+	got, err := mnemonic.ToCipherSeed([]byte("pass"))
+	if err != nil {
+		t.Fatalf("ToCipherSeed returned error: %v", err)
+	}
+
+	if got.InternalVersion != c.InternalVersion {
+		t.Errorf("InternalVersion = %d, want %d", got.InternalVersion, c.InternalVersion)
+	}
+	if got.Birthday != c.Birthday {
+		t.Errorf("Birthday = %d, want %d", got.Birthday, c.Birthday)
+	}
+	if !bytes.Equal(got.Entropy[:], c.Entropy[:]) {
+		t.Errorf("Entropy = %x, want %x", got.Entropy, c.Entropy)
+	}
+}
+
+func TestToCipherSeedWrongPass(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x01}, 16)
+	c, err := aezeed.New(0, entropy, time.Now())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	mnemonic, err := c.ToMnemonic([]byte("correct horse battery staple"))
+	if err != nil {
+		t.Fatalf("ToMnemonic returned error: %v", err)
+	}
+
+	if _, err := mnemonic.ToCipherSeed([]byte("wrong pass")); err != aezeed.ErrInvalidPass {
+		t.Errorf("ToCipherSeed error = %v, want %v", err, aezeed.ErrInvalidPass)
+	}
+}
+
+func TestToCipherSeedCorruptedChecksum(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x02}, 16)
+	c, err := aezeed.New(0, entropy, time.Now())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	mnemonic, err := c.ToMnemonic([]byte("pass"))
+	if err != nil {
+		t.Fatalf("ToMnemonic returned error: %v", err)
+	}
+
+	// Swap the last word for a different one to corrupt the CRC-32 trailer
+	// without touching the word count or wordlist membership.
+	last := len(mnemonic) - 1
+	if mnemonic[last] != "zoo" {
+		mnemonic[last] = "zoo"
+	} else {
+		mnemonic[last] = "zone"
+	}
+
+	if _, err := mnemonic.ToCipherSeed([]byte("pass")); err != aezeed.ErrChecksumMismatch {
+		t.Errorf("ToCipherSeed error = %v, want %v", err, aezeed.ErrChecksumMismatch)
+	}
+}
+
+func TestChangePass(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x03}, 16)
+	c, err := aezeed.New(0, entropy, time.Now())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	oldMnemonic, err := c.ToMnemonic([]byte("old pass"))
+	if err != nil {
+		t.Fatalf("ToMnemonic returned error: %v", err)
+	}
+
+	newMnemonic, err := aezeed.ChangePass(oldMnemonic, []byte("old pass"), []byte("new pass"))
+	if err != nil {
+		t.Fatalf("ChangePass returned error: %v", err)
+	}
+
+	got, err := newMnemonic.ToCipherSeed([]byte("new pass"))
+	if err != nil {
+		t.Fatalf("ToCipherSeed with new pass returned error: %v", err)
+	}
+	if !bytes.Equal(got.Entropy[:], c.Entropy[:]) {
+		t.Errorf("Entropy after ChangePass = %x, want %x", got.Entropy, c.Entropy)
+	}
+
+	if _, err := aezeed.ChangePass(oldMnemonic, []byte("not the old pass"), []byte("new pass")); err != aezeed.ErrInvalidPass {
+		t.Errorf("ChangePass with wrong oldPass error = %v, want %v", err, aezeed.ErrInvalidPass)
+	}
+}
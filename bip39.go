@@ -2,6 +2,7 @@ package bip39
 
 import (
 	"crypto/rand"
+	"io"
 )
 
 // NewEntropy will create random entropy bytes.
@@ -21,6 +22,19 @@ func NewSeed(mnemonic string, password string) ([]byte, error) {
 	return DefaultEncoder.NewSeed(mnemonic, password)
 }
 
+// NewSeedWithErrorChecking creates a hashed seed output given a mnemonic and
+// a passphrase, returning an error if the mnemonic fails checksum
+// validation.
+func NewSeedWithErrorChecking(mnemonic string, passphrase string) ([]byte, error) {
+	return DefaultEncoder.NewSeedWithErrorChecking(mnemonic, passphrase)
+}
+
+// NewSeedFromMnemonic derives a PBKDF2 seed from mnemonic and passphrase,
+// customized by opts. opts may be nil to use the BIP39 defaults.
+func NewSeedFromMnemonic(mnemonic string, passphrase string, opts *SeedOptions) ([]byte, error) {
+	return DefaultEncoder.NewSeedFromMnemonic(mnemonic, passphrase, opts)
+}
+
 // MarshalEntropy will return a string consisting of the mnemonic words for the
 // given entropy.
 func MarshalEntropy(entropy []byte) (string, error) {
@@ -32,6 +46,27 @@ func UnmarshalEntropy(mnemonic string) ([]byte, error) {
 	return DefaultEncoder.UnmarshalEntropy(mnemonic)
 }
 
+// MarshalEntropyTo writes the mnemonic words for entropy to w, space
+// separated. Word indices are read out of a byte buffer; the checksum is
+// still computed through a big.Int.
+func MarshalEntropyTo(w io.Writer, entropy []byte) error {
+	return DefaultEncoder.MarshalEntropyTo(w, entropy)
+}
+
+// UnmarshalEntropyFrom reads a mnemonic from r and decodes it the same way
+// as UnmarshalEntropy, returning the entropy with its checksum bits
+// appended. The checksum is still split off and verified through a big.Int.
+func UnmarshalEntropyFrom(r io.Reader) ([]byte, error) {
+	return DefaultEncoder.UnmarshalEntropyFrom(r)
+}
+
+// EntropyFromMnemonic takes a mnemonic string and returns only its raw
+// entropy, stripping the checksum bits that UnmarshalEntropy leaves
+// attached.
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	return DefaultEncoder.EntropyFromMnemonic(mnemonic)
+}
+
 // validateEntropyBitSize ensures that entropy is the correct size for being a
 // mnemonic.
 func validateEntropyBitSize(bitSize int) error {
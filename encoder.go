@@ -3,14 +3,20 @@ package bip39
 import (
 	"crypto/sha256"
 	"crypto/sha512"
-	"encoding/binary"
+	"io"
+	"io/ioutil"
 	"math/big"
 	"strings"
 
 	"github.com/tyler-smith/go-bip39/wordlists"
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
 )
 
+// ideographicSpace is the separator Japanese mnemonics are joined with, per
+// the BIP39 spec, instead of an ASCII space.
+const ideographicSpace = "　"
+
 var (
 	// DefaultEncoder is the endoder used by package-root level functions.
 	DefaultEncoder = English
@@ -30,7 +36,7 @@ var (
 	Italian = NewEncoder(wordlists.Italian)
 
 	// Japanese is an Encoder initialized with the Japanese wordlist
-	Japanese = NewEncoder(wordlists.Japanese)
+	Japanese = newEncoderWithSeparator(wordlists.Japanese, ideographicSpace)
 
 	// Korean is an Encoder initialized with the Korean wordlist
 	Korean = NewEncoder(wordlists.Korean)
@@ -38,25 +44,33 @@ var (
 	// Spanish is an Encoder initialized with the Spanish wordlist
 	Spanish = NewEncoder(wordlists.Spanish)
 
-	// Some bitwise operands for working with big.Ints
-	bigOne                  = big.NewInt(1)
-	bigTwo                  = big.NewInt(2)
-	last11BitsMask          = big.NewInt(2047)
-	rightShift11BitsDivider = big.NewInt(2048)
+	// Some bitwise operands for working with big.Ints in addChecksum.
+	bigOne = big.NewInt(1)
+	bigTwo = big.NewInt(2)
 )
 
 // Encoder allows marshalling and unmarshaling of random entropy as defined by
 // the BIP39 spec.
 type Encoder struct {
-	wordList []string
-	wordMap  map[string]int
+	wordList  []string
+	wordMap   map[string]int
+	separator string
 }
 
 // NewEncoder returns a new Encoder for the given wordlist.
 func NewEncoder(wordList []string) *Encoder {
+	return newEncoderWithSeparator(wordList, " ")
+}
+
+// newEncoderWithSeparator returns a new Encoder whose mnemonics are joined
+// with separator instead of the default ASCII space. This only exists for
+// wordlists, such as Japanese, whose language convention uses something
+// other than a space to separate words.
+func newEncoderWithSeparator(wordList []string, separator string) *Encoder {
 	e := &Encoder{
-		wordList: wordList,
-		wordMap:  make(map[string]int, len(wordList)),
+		wordList:  wordList,
+		wordMap:   make(map[string]int, len(wordList)),
+		separator: separator,
 	}
 
 	for i, v := range wordList {
@@ -68,57 +82,92 @@ func NewEncoder(wordList []string) *Encoder {
 
 // MarshalEntropy encodes entropy as a mnemonic phrase.
 func (e *Encoder) MarshalEntropy(entropy []byte) (string, error) {
-	// Compute some lengths for convenience
+	var sb strings.Builder
+	if err := e.MarshalEntropyTo(&sb, entropy); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// MarshalEntropyTo writes the mnemonic words for entropy to w, separated by
+// the encoder's separator (an ASCII space, or U+3000 for Japanese). It reads word indices directly out of a byte buffer instead of
+// peeling them off a big.Int one at a time, so callers encoding many
+// mnemonics, or streaming into a buffered writer, save an allocation per
+// word. addChecksum still builds that buffer via big.Int, and its output is
+// right-aligned (any padding needed to reach a whole number of bytes goes
+// at the front) whenever the word count isn't 24 - readBits is offset by
+// padBitLength to line up with that same alignment.
+func (e *Encoder) MarshalEntropyTo(w io.Writer, entropy []byte) error {
 	entropyBitLength := len(entropy) * 8
 	checksumBitLength := entropyBitLength / 32
-	sentenceLength := (entropyBitLength + checksumBitLength) / 11
+	totalBitLength := entropyBitLength + checksumBitLength
+	sentenceLength := totalBitLength / 11
 
-	err := validateEntropyBitSize(entropyBitLength)
-	if err != nil {
-		return "", err
+	if err := validateEntropyBitSize(entropyBitLength); err != nil {
+		return err
 	}
 
-	// Add checksum to entropy
-	entropy = addChecksum(entropy)
+	bufSize := (totalBitLength + 7) / 8
+	padBitLength := bufSize*8 - totalBitLength
+	checksummed := padByteSlice(addChecksum(entropy), bufSize)
 
-	// Break entropy up into sentenceLength chunks of 11 bits
-	// For each word AND mask the rightmost 11 bits and find the word at that index
-	// Then bitshift entropy 11 bits right and repeat
-	// Add to the last empty slot so we can work with LSBs instead of MSB
-
-	// Entropy as an int so we can bitmask without worrying about bytes slices
-	entropyInt := new(big.Int).SetBytes(entropy)
+	for i := 0; i < sentenceLength; i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, e.separator); err != nil {
+				return err
+			}
+		}
 
-	// Slice to hold words in
-	words := make([]string, sentenceLength)
+		index := readBits(checksummed, padBitLength+i*11, 11)
+		if _, err := io.WriteString(w, e.wordList[index]); err != nil {
+			return err
+		}
+	}
 
-	// Throw away big int for AND masking
-	word := big.NewInt(0)
+	return nil
+}
 
-	for i := sentenceLength - 1; i >= 0; i-- {
-		// Get 11 right most bits and bitshift 11 to the right for next time
-		word.And(entropyInt, last11BitsMask)
-		entropyInt.Div(entropyInt, rightShift11BitsDivider)
+// UnmarshalEntropy decodes a mnemonic phrase into its entropy with the
+// checksum bits appended; EntropyFromMnemonic returns the entropy alone.
+// mnemonic is NFKD-normalized before tokenizing, since some wordlists (Japanese, in
+// particular) store their words in decomposed form.
+func (e *Encoder) UnmarshalEntropy(mnemonic string) ([]byte, error) {
+	return e.unmarshalWords(splitMnemonic(norm.NFKD.String(mnemonic)))
+}
 
-		// Get the bytes representing the 11 bits as a 2 byte slice
-		wordBytes := padByteSlice(word.Bytes(), 2)
+// splitMnemonic tokenizes mnemonic on whitespace. strings.Fields already
+// treats the ideographic space (U+3000) used by Japanese mnemonics as
+// whitespace, so the same tokenizer accepts either separator.
+func splitMnemonic(mnemonic string) []string {
+	return strings.Fields(mnemonic)
+}
 
-		// Convert bytes to an index and add that word to the list
-		words[i] = e.wordList[binary.BigEndian.Uint16(wordBytes)]
+// UnmarshalEntropyFrom reads a whitespace-separated mnemonic from r and
+// decodes it with UnmarshalEntropy, returning the entropy with its checksum
+// bits appended. The whole of r is read into memory first, and the checksum
+// is still split off and verified through a big.Int.
+func (e *Encoder) UnmarshalEntropyFrom(r io.Reader) ([]byte, error) {
+	mnemonic, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
-
-	return strings.Join(words, " "), nil
+	return e.UnmarshalEntropy(string(mnemonic))
 }
 
-// UnmarshalEntropy decodes a mnemonic phrase into raw entropy.
-func (e *Encoder) UnmarshalEntropy(mnemonic string) ([]byte, error) {
+// unmarshalWords decodes a tokenized mnemonic by packing the 11-bit word
+// indices into a byte buffer, validating the checksum against addChecksum,
+// and returning that buffer: the entropy with its checksum bits appended. The word indices are written right-aligned within
+// the buffer - any padding needed to reach a whole number of bytes goes at
+// the front - to match the big.Int-based layout addChecksum itself produces
+// whenever the word count isn't 24.
+func (e *Encoder) unmarshalWords(words []string) ([]byte, error) {
 	var (
-		mnemonicSlice    = strings.Fields(mnemonic)
-		wordCount        = len(mnemonicSlice)
-		entropyBitSize   = wordCount * 11
-		checksumBitSize  = entropyBitSize % 32
-		fullByteSize     = (entropyBitSize-checksumBitSize)/8 + 1
-		checksumByteSize = fullByteSize - (fullByteSize % 4)
+		wordCount       = len(words)
+		totalBitLength  = wordCount * 11
+		checksumBitSize = totalBitLength % 32
+		fullByteSize    = (totalBitLength-checksumBitSize)/8 + 1
+		entropyByteSize = (totalBitLength - checksumBitSize) / 8
+		padBitLength    = fullByteSize*8 - totalBitLength
 	)
 
 	// The number of words should be 12, 15, 18, 21 or 24
@@ -126,48 +175,144 @@ func (e *Encoder) UnmarshalEntropy(mnemonic string) ([]byte, error) {
 		return nil, ErrMnemonicLengthInvalid
 	}
 
-	// Convert word indices to a `big.Int` representing the entropy
-	checksummedEntropy := big.NewInt(0)
-	for _, v := range mnemonicSlice {
+	packed := make([]byte, fullByteSize)
+	for i, v := range words {
 		index, ok := e.wordMap[v]
 		if !ok {
 			return nil, ErrMnemonicWordInvalid
 		}
-		checksummedEntropy.Mul(checksummedEntropy, rightShift11BitsDivider)
-		checksummedEntropy.Add(checksummedEntropy, big.NewInt(int64(index)))
+		writeBits(packed, padBitLength+i*11, 11, index)
 	}
 
-	// Calculate the unchecksummed entropy so we can validate that the checksum is
-	// correct
-	checksumModulo := big.NewInt(0).Exp(bigTwo, big.NewInt(int64(checksumBitSize)), nil)
-	rawEntropy := big.NewInt(0).Div(checksummedEntropy, checksumModulo)
-
-	// Convert `big.Int`s to byte padded byte slices
-	rawEntropyBytes := padByteSlice(rawEntropy.Bytes(), checksumByteSize)
-	checksummedEntropyBytes := padByteSlice(checksummedEntropy.Bytes(), fullByteSize)
+	// The checksum bits are the low-order bits of packed as a whole, which
+	// only lines up with a clean byte boundary when there's no padding
+	// (the 24-word case), so pull the raw entropy out via big.Int rather
+	// than slicing packed directly.
+	rawEntropy := new(big.Int).Rsh(new(big.Int).SetBytes(packed), uint(checksumBitSize))
+	rawEntropyBytes := padByteSlice(rawEntropy.Bytes(), entropyByteSize)
 
 	// Validate that the checksum is correct
-	newChecksummedEntropyBytes := padByteSlice(addChecksum(rawEntropyBytes), fullByteSize)
-	if !compareByteSlices(checksummedEntropyBytes, newChecksummedEntropyBytes) {
+	expected := padByteSlice(addChecksum(rawEntropyBytes), fullByteSize)
+	if !compareByteSlices(packed, expected) {
 		return nil, ErrChecksumIncorrect
 	}
 
-	return checksummedEntropyBytes, nil
+	return packed, nil
+}
+
+// EntropyFromMnemonic decodes mnemonic and returns only the raw entropy,
+// discarding the checksum bits that UnmarshalEntropy leaves appended. Unlike
+// UnmarshalEntropy, this is the exact inverse of MarshalEntropy.
+//
+// The checksum bits are the low-order bits of the combined value
+// UnmarshalEntropy returns, not necessarily the trailing byte, so the split
+// is done via big.Int rather than a byte slice.
+func (e *Encoder) EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	checksummedEntropy, err := e.UnmarshalEntropy(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	wordCount := len(splitMnemonic(mnemonic))
+	checksumBitSize := (wordCount * 11) % 32
+	entropyByteSize := (wordCount*11 - checksumBitSize) / 8
+
+	rawEntropy := new(big.Int).Rsh(new(big.Int).SetBytes(checksummedEntropy), uint(checksumBitSize))
+	return padByteSlice(rawEntropy.Bytes(), entropyByteSize), nil
+}
+
+// readBits extracts a bitCount-bit big-endian run from data starting at
+// bitOffset, without allocating.
+func readBits(data []byte, bitOffset, bitCount int) int {
+	value := 0
+	for i := 0; i < bitCount; i++ {
+		pos := bitOffset + i
+		bit := (data[pos/8] >> uint(7-pos%8)) & 1
+		value = value<<1 | int(bit)
+	}
+	return value
+}
+
+// writeBits packs the low bitCount bits of value into data at bitOffset,
+// big-endian. data must start zeroed, since writeBits only ever sets bits.
+func writeBits(data []byte, bitOffset, bitCount, value int) {
+	for i := 0; i < bitCount; i++ {
+		bit := (value >> uint(bitCount-1-i)) & 1
+		if bit != 0 {
+			pos := bitOffset + i
+			data[pos/8] |= 1 << uint(7-pos%8)
+		}
+	}
+}
+
+// SeedOptions customizes how NewSeedFromMnemonic derives a seed from a
+// mnemonic and passphrase.
+type SeedOptions struct {
+	// SkipValidation disables the mnemonic checksum validation that is
+	// otherwise performed before deriving the seed.
+	SkipValidation bool
+
+	// Iterations overrides the PBKDF2 iteration count. Defaults to 2048,
+	// the value mandated by BIP39.
+	Iterations int
+
+	// SaltPrefix overrides the salt prefix prepended to the passphrase.
+	// Defaults to "mnemonic". Electrum-style seeds use a different prefix.
+	SaltPrefix string
+}
+
+func (o *SeedOptions) iterations() int {
+	if o == nil || o.Iterations == 0 {
+		return 2048
+	}
+	return o.Iterations
+}
+
+func (o *SeedOptions) saltPrefix() string {
+	if o == nil || o.SaltPrefix == "" {
+		return "mnemonic"
+	}
+	return o.SaltPrefix
+}
+
+func (o *SeedOptions) skipValidation() bool {
+	return o != nil && o.SkipValidation
 }
 
 // NewSeed creates a hashed seed from given the mnemonic and a password.
 // The mnemonic is checked for validity.
 func (e *Encoder) NewSeed(mnemonic string, password string) ([]byte, error) {
-	_, err := e.UnmarshalEntropy(mnemonic)
-	if err != nil {
-		return nil, err
+	return e.NewSeedFromMnemonic(mnemonic, password, nil)
+}
+
+// NewSeedWithErrorChecking creates a hashed seed output given a mnemonic and
+// a passphrase, returning an error if the mnemonic fails checksum
+// validation. It matches the historical go-bip39 API; NewSeedFromMnemonic
+// additionally lets the derivation be customized via SeedOptions.
+func (e *Encoder) NewSeedWithErrorChecking(mnemonic string, passphrase string) ([]byte, error) {
+	return e.NewSeedFromMnemonic(mnemonic, passphrase, nil)
+}
+
+// NewSeedFromMnemonic derives a PBKDF2 seed from mnemonic and passphrase.
+// opts may be nil to use the BIP39 defaults, or set to customize the salt
+// prefix and iteration count so that variant schemes, such as Electrum's,
+// can be derived through the same code path.
+func (e *Encoder) NewSeedFromMnemonic(mnemonic string, passphrase string, opts *SeedOptions) ([]byte, error) {
+	if !opts.skipValidation() {
+		if _, err := e.UnmarshalEntropy(mnemonic); err != nil {
+			return nil, err
+		}
 	}
-	return createSeedHash(mnemonic, password), nil
+	return createSeedHash(mnemonic, passphrase, opts), nil
 }
 
-// createSeedHash calculates a seed hash from an arbitrary string.
-func createSeedHash(mnemonic string, password string) []byte {
-	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+password), 2048, 64, sha512.New)
+// createSeedHash calculates a seed hash from an arbitrary string. Per the
+// BIP39 spec, both the mnemonic and passphrase are NFKD-normalized before
+// being fed into PBKDF2.
+func createSeedHash(mnemonic string, passphrase string, opts *SeedOptions) []byte {
+	normalizedMnemonic := norm.NFKD.String(mnemonic)
+	normalizedPassphrase := norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(normalizedMnemonic), []byte(opts.saltPrefix()+normalizedPassphrase), opts.iterations(), 64, sha512.New)
 }
 
 // addChecksum appends to data the first (len(data) / 32)bits of the result of
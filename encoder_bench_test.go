@@ -0,0 +1,54 @@
+package bip39_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/rhizomplatform/go-bip39"
+)
+
+func BenchmarkMarshalEntropy(b *testing.B) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := bip39.English.MarshalEntropy(entropy); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalEntropyTo(b *testing.B) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := bip39.English.MarshalEntropyTo(ioutil.Discard, entropy); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalEntropy(b *testing.B) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		b.Fatal(err)
+	}
+	mnemonic, err := bip39.English.MarshalEntropy(entropy)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := bip39.English.UnmarshalEntropy(mnemonic); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
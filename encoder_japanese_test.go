@@ -0,0 +1,34 @@
+package bip39_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/rhizomplatform/go-bip39"
+)
+
+// TestNewSeedJapanese checks a Japanese mnemonic/passphrase pair whose words
+// are stored in NFD-decomposed form in wordlists.Japanese (e.g. "ぞ" as
+// "そ"+U+3099) against a seed computed independently via PBKDF2-HMAC-SHA512
+// over the NFKD-normalized mnemonic and passphrase, to make sure
+// UnmarshalEntropy and NewSeedWithErrorChecking normalize consistently.
+func TestNewSeedJapanese(t *testing.T) {
+	const (
+		mnemonic    = "あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あおぞら"
+		passphrase  = "%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%"
+		wantSeedHex = "ed446bef6bb6afe5aa4e12c29247f58fba2ac7be68da22b252ddd48c5cae4ad7252ff6e5e9f661aabad2812eff3b447437d096bffedc61ff4bc23fc2c58cf2fb"
+	)
+
+	if _, err := bip39.Japanese.UnmarshalEntropy(mnemonic); err != nil {
+		t.Fatalf("UnmarshalEntropy(%q) returned error: %v", mnemonic, err)
+	}
+
+	seed, err := bip39.Japanese.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		t.Fatalf("NewSeedWithErrorChecking returned error: %v", err)
+	}
+
+	if got := hex.EncodeToString(seed); got != wantSeedHex {
+		t.Errorf("seed = %s, want %s", got, wantSeedHex)
+	}
+}
@@ -0,0 +1,39 @@
+package bip39_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rhizomplatform/go-bip39"
+)
+
+// TestMarshalUnmarshalEntropyRoundTrip exercises every supported entropy
+// size to guard against MarshalEntropyTo/UnmarshalEntropy disagreeing with
+// addChecksum about where the zero-padding bits for a non-byte-aligned word
+// count go.
+func TestMarshalUnmarshalEntropyRoundTrip(t *testing.T) {
+	for bitSize := 128; bitSize <= 256; bitSize += 32 {
+		entropy, err := bip39.NewEntropy(bitSize)
+		if err != nil {
+			t.Fatalf("NewEntropy(%d) returned error: %v", bitSize, err)
+		}
+
+		mnemonic, err := bip39.English.MarshalEntropy(entropy)
+		if err != nil {
+			t.Fatalf("MarshalEntropy(%d-bit entropy) returned error: %v", bitSize, err)
+		}
+
+		if _, err := bip39.English.UnmarshalEntropy(mnemonic); err != nil {
+			t.Fatalf("UnmarshalEntropy(%q) returned error: %v", mnemonic, err)
+		}
+
+		entropyBack, err := bip39.English.EntropyFromMnemonic(mnemonic)
+		if err != nil {
+			t.Fatalf("EntropyFromMnemonic(%q) returned error: %v", mnemonic, err)
+		}
+
+		if !bytes.Equal(entropyBack, entropy) {
+			t.Errorf("%d-bit entropy: got %x after round trip, want %x", bitSize, entropyBack, entropy)
+		}
+	}
+}
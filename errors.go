@@ -0,0 +1,21 @@
+package bip39
+
+import "errors"
+
+var (
+	// ErrEntropyLengthInvalid is returned when entropy's bit length isn't a
+	// multiple of 32 in the inclusive range {128,256}.
+	ErrEntropyLengthInvalid = errors.New("bip39: entropy length must be a multiple of 32 between 128 and 256")
+
+	// ErrMnemonicLengthInvalid is returned when a mnemonic doesn't contain
+	// 12, 15, 18, 21 or 24 words.
+	ErrMnemonicLengthInvalid = errors.New("bip39: mnemonic must contain 12, 15, 18, 21 or 24 words")
+
+	// ErrMnemonicWordInvalid is returned when a mnemonic contains a word
+	// outside the encoder's wordlist.
+	ErrMnemonicWordInvalid = errors.New("bip39: mnemonic contains an unknown word")
+
+	// ErrChecksumIncorrect is returned when a mnemonic's checksum bits don't
+	// match the checksum computed from its entropy.
+	ErrChecksumIncorrect = errors.New("bip39: mnemonic checksum is incorrect")
+)
@@ -0,0 +1,96 @@
+package bip39
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrAmbiguousLanguage is returned by DetectLanguage when a mnemonic's
+	// words are all valid in more than one registered wordlist.
+	ErrAmbiguousLanguage = errors.New("bip39: mnemonic matches more than one registered wordlist")
+
+	// ErrUnknownLanguage is returned by DetectLanguage when a mnemonic's
+	// words do not match any registered wordlist.
+	ErrUnknownLanguage = errors.New("bip39: mnemonic does not match any registered wordlist")
+)
+
+var (
+	encoderRegistryMu sync.RWMutex
+	encoderRegistry   = map[string]*Encoder{
+		"chinese_simplified":  ChineseSimplified,
+		"chinese_traditional": ChineseTraditional,
+		"english":             English,
+		"italian":             Italian,
+		"japanese":            Japanese,
+		"korean":              Korean,
+		"spanish":             Spanish,
+	}
+)
+
+// RegisterEncoder adds e to the set of encoders consulted by DetectLanguage
+// and UnmarshalEntropyAuto, keyed by name. It lets applications plug in
+// wordlists, such as Czech or Portuguese, that are not bundled with this
+// package.
+func RegisterEncoder(name string, e *Encoder) {
+	encoderRegistryMu.Lock()
+	defer encoderRegistryMu.Unlock()
+	encoderRegistry[name] = e
+}
+
+// DetectLanguage returns the registered Encoder whose wordlist contains
+// every word in mnemonic. It returns ErrAmbiguousLanguage if more than one
+// wordlist matches, and ErrUnknownLanguage if none do.
+func DetectLanguage(mnemonic string) (*Encoder, error) {
+	words := splitMnemonic(norm.NFKD.String(mnemonic))
+	if len(words) == 0 {
+		return nil, ErrUnknownLanguage
+	}
+
+	encoderRegistryMu.RLock()
+	defer encoderRegistryMu.RUnlock()
+
+	var match *Encoder
+	for _, e := range encoderRegistry {
+		if !e.containsAllWords(words) {
+			continue
+		}
+
+		if match != nil {
+			return nil, ErrAmbiguousLanguage
+		}
+		match = e
+	}
+
+	if match == nil {
+		return nil, ErrUnknownLanguage
+	}
+	return match, nil
+}
+
+// UnmarshalEntropyAuto detects the mnemonic's wordlist language and decodes
+// it into raw entropy in a single step.
+func UnmarshalEntropyAuto(mnemonic string) ([]byte, *Encoder, error) {
+	e, err := DetectLanguage(mnemonic)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entropy, err := e.EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entropy, e, nil
+}
+
+// containsAllWords reports whether every word is present in e's wordlist.
+func (e *Encoder) containsAllWords(words []string) bool {
+	for _, w := range words {
+		if _, ok := e.wordMap[w]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package bip39_test
+
+import (
+	"testing"
+
+	"github.com/rhizomplatform/go-bip39"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		want     *bip39.Encoder
+	}{
+		{"english", "abandon", bip39.English},
+		{"italian", "abaco", bip39.Italian},
+		{"spanish", "ábaco", bip39.Spanish},
+		{"korean", "가격", bip39.Korean},
+		{"japanese", "あいこくしん", bip39.Japanese},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bip39.DetectLanguage(tt.mnemonic)
+			if err != nil {
+				t.Fatalf("DetectLanguage(%q) returned error: %v", tt.mnemonic, err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %p, want %p", tt.mnemonic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLanguageAmbiguous(t *testing.T) {
+	// "一" is a word in both the Chinese Simplified and Chinese Traditional
+	// wordlists, so it matches more than one registered encoder.
+	if _, err := bip39.DetectLanguage("一"); err != bip39.ErrAmbiguousLanguage {
+		t.Errorf("DetectLanguage(%q) error = %v, want %v", "一", err, bip39.ErrAmbiguousLanguage)
+	}
+}
+
+func TestDetectLanguageUnknown(t *testing.T) {
+	if _, err := bip39.DetectLanguage("foo bar baz"); err != bip39.ErrUnknownLanguage {
+		t.Errorf("DetectLanguage error = %v, want %v", err, bip39.ErrUnknownLanguage)
+	}
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	custom := bip39.NewEncoder([]string{"zzzcustomword", "anothercustomword"})
+	bip39.RegisterEncoder("custom_test_language", custom)
+
+	got, err := bip39.DetectLanguage("zzzcustomword anothercustomword")
+	if err != nil {
+		t.Fatalf("DetectLanguage returned error: %v", err)
+	}
+	if got != custom {
+		t.Errorf("DetectLanguage did not pick up the encoder registered via RegisterEncoder")
+	}
+}
+
+func TestUnmarshalEntropyAuto(t *testing.T) {
+	const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	entropy, enc, err := bip39.UnmarshalEntropyAuto(mnemonic)
+	if err != nil {
+		t.Fatalf("UnmarshalEntropyAuto returned error: %v", err)
+	}
+	if enc != bip39.English {
+		t.Errorf("UnmarshalEntropyAuto detected %p, want English encoder", enc)
+	}
+	if len(entropy) != 16 {
+		t.Errorf("len(entropy) = %d, want 16", len(entropy))
+	}
+}